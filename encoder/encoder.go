@@ -0,0 +1,81 @@
+// Package encoder writes a stream of records to a writer one at a time,
+// in a choice of formats, instead of marshaling a whole slice at once.
+package encoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Format selects how consecutive records are framed on the wire.
+type Format string
+
+const (
+	// FormatPretty indents each record by two spaces and wraps the stream in
+	// a JSON array. Records start at the array's left margin, and each comma
+	// between records sits on its own line.
+	FormatPretty Format = "pretty"
+	// FormatCompact wraps the stream in a JSON array like FormatPretty, but
+	// without indentation.
+	FormatCompact Format = "compact"
+	// FormatNDJSON writes one JSON object per line with no wrapping array,
+	// so the file can be read and appended to a record at a time.
+	FormatNDJSON Format = "ndjson"
+)
+
+// Encoder writes a stream of records to w in the given Format. Callers must
+// call Close once every record has been written to flush the closing `]`
+// for the array formats.
+type Encoder struct {
+	w       io.Writer
+	format  Format
+	enc     *json.Encoder
+	started bool
+}
+
+// New returns an Encoder writing to w in format. An unrecognized format
+// behaves like FormatCompact.
+func New(w io.Writer, format Format) *Encoder {
+	enc := json.NewEncoder(w)
+	if format == FormatPretty {
+		enc.SetIndent("", "  ")
+	}
+	return &Encoder{w: w, format: format, enc: enc}
+}
+
+// Encode writes v as the next record in the stream.
+func (e *Encoder) Encode(v any) error {
+	if e.format == FormatNDJSON {
+		return e.enc.Encode(v)
+	}
+
+	sep := ",\n"
+	if !e.started {
+		sep = "[\n"
+		e.started = true
+	}
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return fmt.Errorf("write separator: %w", err)
+	}
+
+	return e.enc.Encode(v)
+}
+
+// Close terminates the stream, writing the closing `]` for the array
+// formats. It's a no-op for FormatNDJSON.
+func (e *Encoder) Close() error {
+	if e.format == FormatNDJSON {
+		return nil
+	}
+
+	closing := "[]\n"
+	if e.started {
+		closing = "]\n"
+	}
+	if _, err := io.WriteString(e.w, closing); err != nil {
+		return fmt.Errorf("write closing bracket: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-data-fetcher/encoder"
+	"go-data-fetcher/pipeline"
+)
+
+// fetchAllOptions is configured by Option and controls how FetchAllData runs
+// the fetch -> aggregate -> write pipeline.
+type fetchAllOptions struct {
+	requestTimeout   time.Duration
+	overallDeadline  time.Duration
+	cancelOnFirstErr bool
+	outputFormat     encoder.Format
+}
+
+// Option configures FetchAllData.
+type Option func(*fetchAllOptions)
+
+// WithRequestTimeout bounds how long any single HTTP request may take.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(o *fetchAllOptions) { o.requestTimeout = d }
+}
+
+// WithOverallDeadline bounds the whole pipeline run, fetch through write.
+func WithOverallDeadline(d time.Duration) Option {
+	return func(o *fetchAllOptions) { o.overallDeadline = d }
+}
+
+// WithCancelOnFirstError cancels the rest of the pipeline as soon as any
+// stage reports an error, instead of letting every in-flight request finish.
+func WithCancelOnFirstError(enabled bool) Option {
+	return func(o *fetchAllOptions) { o.cancelOnFirstErr = enabled }
+}
+
+// WithOutputFormat selects how writeStage frames the records it writes.
+func WithOutputFormat(f encoder.Format) Option {
+	return func(o *fetchAllOptions) { o.outputFormat = f }
+}
+
+// FetchAllData runs the fetch -> aggregate -> write pipeline to completion,
+// threading ctx through every stage so an outer timeout or cancellation
+// aborts in-flight requests and unblocks any goroutine blocked on a channel
+// send, instead of leaking it. It returns every error reported by any stage,
+// joined together, or nil if all three stages succeeded.
+func FetchAllData(ctx context.Context, filename string, opts ...Option) error {
+	cfg := fetchAllOptions{requestTimeout: 10 * time.Second, outputFormat: encoder.FormatPretty}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.overallDeadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.overallDeadline)
+		defer cancel()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fetchOut, fetchErrc, err := (fetchStage{requestTimeout: cfg.requestTimeout}).Do(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	aggOut, aggErrc, err := (aggregateStage{}).Do(ctx, fetchOut)
+	if err != nil {
+		return err
+	}
+
+	_, writeErrc, err := (writeStage{filename: filename, format: cfg.outputFormat}).Do(ctx, aggOut)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for stageErr := range pipeline.MergeErrors(fetchErrc, aggErrc, writeErrc) {
+		errs = append(errs, stageErr)
+		if cfg.cancelOnFirstErr {
+			cancel()
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFetchAllData_CancelledContextReturnsError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	out := filepath.Join(t.TempDir(), "data.json")
+	if err := FetchAllData(ctx, out); err == nil {
+		t.Fatal("FetchAllData with an already-cancelled context: got nil error, want non-nil")
+	}
+}
+
+func TestFetchAllData_PersistentlyFailingEndpointDoesNotDeadlock(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	origUsers, origPosts, origComments := GET_USERS_URL, GET_POSTS_URL, GET_COMMENTS_URL
+	GET_USERS_URL, GET_POSTS_URL, GET_COMMENTS_URL = srv.URL, srv.URL, srv.URL
+	defer func() {
+		GET_USERS_URL, GET_POSTS_URL, GET_COMMENTS_URL = origUsers, origPosts, origComments
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	out := filepath.Join(t.TempDir(), "data.json")
+	if err := FetchAllData(ctx, out, WithRequestTimeout(time.Second)); err == nil {
+		t.Fatal("FetchAllData with a persistently-failing endpoint: got nil error, want non-nil")
+	}
+}
@@ -0,0 +1,178 @@
+// Package fetcher fetches a list of Endpoints concurrently, bounded by a
+// semaphore, and streams back a typed Result per endpoint.
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Endpoint describes a single resource to fetch.
+type Endpoint struct {
+	Name string
+	URL  string
+}
+
+// Result carries either the decoded payload for an Endpoint or the error
+// that occurred while fetching/decoding it, never both.
+type Result[T any] struct {
+	Endpoint Endpoint
+	Data     T
+	Err      error
+}
+
+// Fetcher bounds how many HTTP requests are in flight at once across the
+// endpoints it's asked to fetch, and retries the ones that fail with a
+// transient error.
+type Fetcher struct {
+	sem            chan struct{}
+	client         *http.Client
+	retry          RetryPolicy
+	requestTimeout time.Duration
+}
+
+// New returns a Fetcher that allows at most maxConcurrency requests in
+// flight at the same time, retrying transient failures with
+// DefaultRetryPolicy. maxConcurrency <= 0 is treated as 1.
+func New(maxConcurrency int) *Fetcher {
+	if maxConcurrency <= 0 {
+		maxConcurrency = 1
+	}
+	return &Fetcher{
+		sem:    make(chan struct{}, maxConcurrency),
+		client: http.DefaultClient,
+		retry:  DefaultRetryPolicy,
+	}
+}
+
+// WithRetryPolicy overrides f's retry policy and returns f for chaining.
+func (f *Fetcher) WithRetryPolicy(p RetryPolicy) *Fetcher {
+	f.retry = p
+	return f
+}
+
+// WithRequestTimeout bounds how long a single HTTP request (including
+// retries, each gets its own budget) may take before it's cancelled.
+// timeout <= 0 disables the per-request timeout. Returns f for chaining.
+func (f *Fetcher) WithRequestTimeout(timeout time.Duration) *Fetcher {
+	f.requestTimeout = timeout
+	return f
+}
+
+// Fetch fans-out one goroutine per endpoint, bounded by f's semaphore, and
+// streams a Result[T] per endpoint as soon as it's fetched and decoded. The
+// returned channel is closed once every endpoint has been accounted for.
+// Fetch respects ctx: a cancelled context unblocks any goroutine waiting to
+// acquire the semaphore or to send its Result.
+func Fetch[T any](ctx context.Context, f *Fetcher, endpoints []Endpoint) <-chan Result[T] {
+	out := make(chan Result[T])
+
+	go func() {
+		defer close(out)
+
+		var wg sync.WaitGroup
+		wg.Add(len(endpoints))
+
+		for _, ep := range endpoints {
+			go func(ep Endpoint) {
+				defer wg.Done()
+
+				select {
+				case f.sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				defer func() { <-f.sem }()
+
+				var data T
+				err := f.get(ctx, ep, &data)
+				if err != nil {
+					var zero T
+					data = zero
+				}
+
+				select {
+				case out <- Result[T]{Endpoint: ep, Data: data, Err: err}:
+				case <-ctx.Done():
+				}
+			}(ep)
+		}
+
+		wg.Wait()
+	}()
+
+	return out
+}
+
+// get fetches ep.URL into v, retrying network errors and 5xx responses with
+// exponential backoff for up to f.retry.MaxAttempts attempts in all. A 4xx response or a
+// decode failure is returned immediately — retrying won't fix either. ctx
+// cancellation (an outer timeout or the caller giving up) aborts both the
+// backoff wait and any in-flight request.
+func (f *Fetcher) get(ctx context.Context, ep Endpoint, v any) error {
+	var lastErr error
+
+	for attempt := 1; attempt <= f.retry.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(f.retry.backoff(attempt - 1)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		retryable, err := f.doOnce(ctx, ep, v)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !retryable {
+			return err
+		}
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// doOnce performs a single attempt at fetching and decoding ep. retryable
+// reports whether the failure is transient (network error, 5xx) and worth
+// retrying, as opposed to a 4xx or a decode error.
+func (f *Fetcher) doOnce(ctx context.Context, ep Endpoint, v any) (retryable bool, err error) {
+	reqCtx := ctx
+	if f.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, f.requestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, ep.URL, nil)
+	if err != nil {
+		return false, fmt.Errorf("build request %s: %w", ep.URL, err)
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("get %s: %w", ep.URL, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		return true, fmt.Errorf("get %s: server error %s", ep.URL, res.Status)
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		return false, fmt.Errorf("get %s: client error %s", ep.URL, res.Status)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return false, fmt.Errorf("decode %s: %w", ep.URL, err)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,36 @@
+package fetcher
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how many times a failed request is retried and how
+// long to wait between attempts. Only network errors and 5xx responses are
+// retried — a 4xx means the request itself is wrong and retrying won't help.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy retries up to twice more after the first attempt, with
+// exponential backoff capped at 2s and jittered to avoid every endpoint
+// retrying in lockstep.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// backoff returns how long to wait before the given attempt (1-indexed: the
+// delay before the 2nd attempt, the 3rd, ...), with up to 50% jitter.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << uint(attempt-1)
+	if delay > p.MaxDelay || delay <= 0 {
+		delay = p.MaxDelay
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay/2 + jitter
+}
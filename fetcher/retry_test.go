@@ -0,0 +1,17 @@
+package fetcher
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	for attempt := 1; attempt <= 5; attempt++ {
+		d := p.backoff(attempt)
+		if d < 0 || d > p.MaxDelay {
+			t.Fatalf("backoff(%d) = %v, want within [0, %v]", attempt, d, p.MaxDelay)
+		}
+	}
+}
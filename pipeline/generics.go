@@ -0,0 +1,105 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Fetch performs a single HTTP GET against url and decodes the JSON body
+// into a []T. It's the simplest possible source stage — one goroutine, one
+// send — for composing ad hoc fetchers without rewriting the same
+// "GET + decode + report on a channel" shape once per resource type.
+func Fetch[T any](ctx context.Context, url string) (<-chan []T, <-chan error) {
+	out := make(chan []T, 1)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errc)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			errc <- fmt.Errorf("build request %s: %w", url, err)
+			return
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			errc <- fmt.Errorf("get %s: %w", url, err)
+			return
+		}
+		defer res.Body.Close()
+
+		var data []T
+		if err := json.NewDecoder(res.Body).Decode(&data); err != nil {
+			errc <- fmt.Errorf("decode %s: %w", url, err)
+			return
+		}
+
+		select {
+		case out <- data:
+		case <-ctx.Done():
+		}
+	}()
+
+	return out, errc
+}
+
+// Merge fans-in any number of same-typed channels into one, closing the
+// returned channel once every source channel has been drained and closed.
+// MergeErrors is Merge specialized to error channels.
+func Merge[T any](cs ...<-chan T) <-chan T {
+	var wg sync.WaitGroup
+	out := make(chan T)
+
+	output := func(c <-chan T) {
+		defer wg.Done()
+		for v := range c {
+			out <- v
+		}
+	}
+
+	wg.Add(len(cs))
+	for _, c := range cs {
+		go output(c)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// OrDone wraps in so that ranging over the returned channel also stops once
+// done is closed, instead of blocking forever if in never closes on its
+// own. Use it when consuming a channel whose producer you can't trust to
+// honor cancellation itself.
+func OrDone[T any](done <-chan struct{}, in <-chan T) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- v:
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return out
+}
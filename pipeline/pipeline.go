@@ -0,0 +1,32 @@
+// Package pipeline lets fetch/aggregate/write stages be composed by
+// daisy-chaining channels instead of hard-coding the orchestration:
+//
+//	fetchOut, fetchErrc, err := fetchStage.Do(ctx, nil)
+//	aggOut, aggErrc, err := aggregateStage.Do(ctx, fetchOut)
+//	_, writeErrc, err := writeStage.Do(ctx, aggOut)
+//	errc := MergeErrors(fetchErrc, aggErrc, writeErrc)
+package pipeline
+
+import (
+	"context"
+)
+
+// Message is the unit of data exchanged between stages. Stages agree on the
+// concrete type flowing through them out of band (e.g. a fetch stage emits
+// the fetched users, posts and comments together, and an aggregate stage
+// emits one UserWithPostsAndComments per user).
+type Message any
+
+// Stage is a single step of a pipeline. Do must return promptly: the actual
+// work happens in goroutines it starts, which must honor ctx cancellation.
+type Stage interface {
+	Do(ctx context.Context, in <-chan Message) (out <-chan Message, errc <-chan error, err error)
+}
+
+// MergeErrors fans-in any number of error channels into one, closing the
+// returned channel once every source channel has been drained and closed,
+// so the caller only has to range over a single channel. It's Merge
+// specialized to error channels (see generics.go).
+func MergeErrors(cs ...<-chan error) <-chan error {
+	return Merge(cs...)
+}
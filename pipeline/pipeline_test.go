@@ -0,0 +1,54 @@
+package pipeline
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+func TestMerge_FansInAllValuesAndCloses(t *testing.T) {
+	a := make(chan int, 2)
+	b := make(chan int, 1)
+	a <- 1
+	a <- 2
+	close(a)
+	b <- 3
+	close(b)
+
+	var got []int
+	for v := range Merge(a, b) {
+		got = append(got, v)
+	}
+
+	sort.Ints(got)
+	want := []int{1, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestMergeErrors_ClosesOnceEverySourceCloses(t *testing.T) {
+	errA := make(chan error, 1)
+	errB := make(chan error)
+
+	wantErr := errors.New("boom")
+	errA <- wantErr
+	close(errA)
+	close(errB)
+
+	merged := MergeErrors(errA, errB)
+
+	got, ok := <-merged
+	if !ok || got != wantErr {
+		t.Fatalf("got (%v, %v), want (%v, true)", got, ok, wantErr)
+	}
+
+	if _, ok := <-merged; ok {
+		t.Fatal("expected merged channel to be closed once both sources close")
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"go-data-fetcher/encoder"
+	"go-data-fetcher/fetcher"
+	"go-data-fetcher/pipeline"
+)
+
+// rawData is what fetchStage emits: the three resources fetched from
+// JSONPlaceholder, still unrelated to each other.
+type rawData struct {
+	users    []User
+	posts    []Post
+	comments []Comment
+}
+
+// fetchStage is the first stage of the pipeline: it ignores its input
+// channel, fetches users/posts/comments concurrently, and emits a single
+// rawData Message once all three have either succeeded or failed. If ctx is
+// cancelled it emits nothing and reports ctx.Err() instead.
+type fetchStage struct {
+	// requestTimeout bounds each individual HTTP request. Zero means no
+	// per-request timeout is applied, only ctx cancellation.
+	requestTimeout time.Duration
+}
+
+func (s fetchStage) Do(ctx context.Context, _ <-chan pipeline.Message) (<-chan pipeline.Message, <-chan error, error) {
+	out := make(chan pipeline.Message, 1)
+	errc := make(chan error, 4)
+
+	f := fetcher.New(maxConcurrency).WithRequestTimeout(s.requestTimeout)
+
+	usersCh := fetcher.Fetch[[]User](ctx, f, []fetcher.Endpoint{{Name: "users", URL: GET_USERS_URL}})
+	postsCh := fetcher.Fetch[[]Post](ctx, f, []fetcher.Endpoint{{Name: "posts", URL: GET_POSTS_URL}})
+	commentsCh := fetcher.Fetch[[]Comment](ctx, f, []fetcher.Endpoint{{Name: "comments", URL: GET_COMMENTS_URL}})
+
+	var (
+		wg   sync.WaitGroup
+		data rawData
+	)
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for res := range usersCh {
+			if res.Err != nil {
+				errc <- res.Err
+				continue
+			}
+			data.users = res.Data
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for res := range postsCh {
+			if res.Err != nil {
+				errc <- res.Err
+				continue
+			}
+			data.posts = res.Data
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for res := range commentsCh {
+			if res.Err != nil {
+				errc <- res.Err
+				continue
+			}
+			data.comments = res.Data
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		defer close(out)
+		defer close(errc)
+
+		if ctx.Err() != nil {
+			errc <- ctx.Err()
+			return
+		}
+
+		select {
+		case out <- data:
+		case <-ctx.Done():
+			errc <- ctx.Err()
+		}
+	}()
+
+	return out, errc, nil
+}
+
+// aggregateStage consumes the rawData produced by fetchStage and streams it
+// out as one UserWithPostsAndComments Message per user.
+type aggregateStage struct{}
+
+func (aggregateStage) Do(ctx context.Context, in <-chan pipeline.Message) (<-chan pipeline.Message, <-chan error, error) {
+	out := make(chan pipeline.Message)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errc)
+
+		select {
+		case msg, ok := <-in:
+			if !ok {
+				return
+			}
+			raw := msg.(rawData)
+
+			records := make(chan UserWithPostsAndComments)
+			go func() {
+				defer close(records)
+				StreamAggregateData(ctx, raw.users, raw.posts, raw.comments, records)
+			}()
+
+			for record := range records {
+				select {
+				case out <- record:
+				case <-ctx.Done():
+					errc <- ctx.Err()
+					return
+				}
+			}
+		case <-ctx.Done():
+			errc <- ctx.Err()
+		}
+	}()
+
+	return out, errc, nil
+}
+
+// writeStage consumes a stream of UserWithPostsAndComments and encodes each
+// one to filename as it arrives.
+type writeStage struct {
+	filename string
+	format   encoder.Format
+}
+
+func (s writeStage) Do(ctx context.Context, in <-chan pipeline.Message) (<-chan pipeline.Message, <-chan error, error) {
+	out := make(chan pipeline.Message)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errc)
+
+		file, err := os.OpenFile(s.filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			errc <- err
+			return
+		}
+		defer file.Close()
+
+		enc := encoder.New(file, s.format)
+
+		for {
+			select {
+			case msg, ok := <-in:
+				if !ok {
+					if err := enc.Close(); err != nil {
+						errc <- err
+						return
+					}
+					log.Println("✅ JSON file save successfully", s.filename)
+					return
+				}
+				record := msg.(UserWithPostsAndComments)
+				if err := enc.Encode(record); err != nil {
+					errc <- err
+					return
+				}
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return out, errc, nil
+}